@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/ciliverse/cilikube/pkg/cluster"
+	"github.com/gin-gonic/gin"
+)
+
+// ClusterHandler 负责集群注册表的增删查以及健康状态查询
+type ClusterHandler struct {
+	manager *cluster.Manager
+}
+
+// NewClusterHandler ...
+func NewClusterHandler(manager *cluster.Manager) *ClusterHandler {
+	return &ClusterHandler{manager: manager}
+}
+
+// addClusterRequest 是注册一个新集群的请求体。
+// KubeconfigBase64 用于 upload/secret 两种来源；InCluster 为 true 时表示注册宿主集群自身，
+// 此时忽略 KubeconfigBase64。
+type addClusterRequest struct {
+	Name             string `json:"name" binding:"required"`
+	Source           string `json:"source"` // upload | secret，默认 upload；in-cluster 由 InCluster 字段选择
+	KubeconfigBase64 string `json:"kubeconfigBase64"`
+	InCluster        bool   `json:"inCluster"`
+}
+
+// clusterResponse 是对外暴露的集群信息，不包含Config/Clientset等内部字段
+type clusterResponse struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Status      string `json:"status"`
+	LastChecked string `json:"lastChecked,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+}
+
+func toClusterResponse(c *cluster.Cluster) clusterResponse {
+	status, lastChecked, lastErr := c.Status()
+	resp := clusterResponse{Name: c.Name, Source: string(c.Source), Status: string(status)}
+	if !lastChecked.IsZero() {
+		resp.LastChecked = lastChecked.Format("2006-01-02T15:04:05Z07:00")
+	}
+	resp.LastError = lastErr
+	return resp
+}
+
+// ListClusters 返回全部已注册集群及其最近一次健康探测结果
+func (h *ClusterHandler) ListClusters(c *gin.Context) {
+	clusters := h.manager.List()
+	items := make([]clusterResponse, 0, len(clusters))
+	for _, cl := range clusters {
+		items = append(items, toClusterResponse(cl))
+	}
+	respondSuccess(c, http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
+// AddCluster 注册一个新集群：上传/Secret引用的kubeconfig，或直接使用in-cluster配置。
+// 注册一个集群等价于让后端持有一份任意apiserver地址的凭据（SSRF风险：kubeconfig的
+// server字段可指向内网/云元数据地址），因此除了鉴权中间件的身份校验外，这里再要求
+// 调用方具备admin角色，避免任何已登录用户都能借此探测内网。
+func (h *ClusterHandler) AddCluster(c *gin.Context) {
+	if role := c.GetString("role"); role != "admin" {
+		respondError(c, http.StatusForbidden, "注册集群需要admin角色")
+		return
+	}
+
+	var req addClusterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	if _, err := h.manager.Get(req.Name); err == nil {
+		respondError(c, http.StatusConflict, "集群已存在")
+		return
+	}
+
+	if req.InCluster {
+		registered, err := h.manager.RegisterInCluster(req.Name)
+		if err != nil {
+			if errors.Is(err, cluster.ErrAlreadyRegistered) {
+				respondError(c, http.StatusConflict, "集群已存在")
+				return
+			}
+			respondError(c, http.StatusInternalServerError, "注册集群失败: "+err.Error())
+			return
+		}
+		respondSuccess(c, http.StatusOK, toClusterResponse(registered))
+		return
+	}
+
+	if req.KubeconfigBase64 == "" {
+		respondError(c, http.StatusBadRequest, "kubeconfigBase64 不能为空")
+		return
+	}
+
+	kubeconfig, err := base64.StdEncoding.DecodeString(req.KubeconfigBase64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "kubeconfigBase64 解码失败: "+err.Error())
+		return
+	}
+
+	source := cluster.SourceUpload
+	if req.Source == string(cluster.SourceSecret) {
+		source = cluster.SourceSecret
+	}
+
+	registered, err := h.manager.RegisterFromKubeconfig(req.Name, source, kubeconfig)
+	if err != nil {
+		if errors.Is(err, cluster.ErrAlreadyRegistered) {
+			respondError(c, http.StatusConflict, "集群已存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "注册集群失败: "+err.Error())
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, toClusterResponse(registered))
+}
+
+// RemoveCluster 注销一个已注册集群
+func (h *ClusterHandler) RemoveCluster(c *gin.Context) {
+	name := c.Param("cluster")
+	if _, err := h.manager.Get(name); err != nil {
+		respondError(c, http.StatusNotFound, "集群不存在")
+		return
+	}
+
+	h.manager.Remove(name)
+	respondSuccess(c, http.StatusOK, gin.H{"message": "注销成功"})
+}
+
+// GetClusterHealth 返回单个集群最近一次健康探测的详细结果
+func (h *ClusterHandler) GetClusterHealth(c *gin.Context) {
+	name := c.Param("cluster")
+	target, err := h.manager.Get(name)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "集群不存在")
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, toClusterResponse(target))
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ciliverse/cilikube/pkg/utils"
+	"github.com/ciliverse/cilikube/pkg/utils/diff"
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DiffDeploymentCreate dry-run创建所提交的Deployment，返回与命名空间中同名对象（若存在）的diff
+func (h *DeploymentHandler) DiffDeploymentCreate(c *gin.Context) {
+	namespace := c.Param("namespace")
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+
+	data, ok := readManifestBody(c)
+	if !ok {
+		return
+	}
+
+	deployment, err := utils.ParseDeploymentFromFile(data)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "解析Deployment对象失败: "+err.Error())
+		return
+	}
+	if deployment.Namespace == "" {
+		deployment.Namespace = namespace
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	dryRun, err := svc.CreateDryRun(namespace, deployment)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Dry-run创建Deployment失败: "+err.Error())
+		return
+	}
+
+	live, err := svc.Get(namespace, deployment.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		respondError(c, http.StatusInternalServerError, "获取当前Deployment失败: "+err.Error())
+		return
+	}
+
+	result, err := diff.Compute(live, dryRun)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "计算diff失败: "+err.Error())
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, result)
+}
+
+// DiffDeployment dry-run更新指定Deployment，返回与当前live对象之间的diff
+func (h *DeploymentHandler) DiffDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+
+	data, ok := readManifestBody(c)
+	if !ok {
+		return
+	}
+
+	updateDeployment, err := utils.ParseDeploymentFromFile(data)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "解析Deployment对象失败: "+err.Error())
+		return
+	}
+	if updateDeployment.Namespace == "" {
+		updateDeployment.Namespace = namespace
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	live, err := svc.Get(namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "Deployment不存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "获取当前Deployment失败: "+err.Error())
+		return
+	}
+
+	dryRun, err := svc.UpdateDryRun(namespace, name, updateDeployment)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "Dry-run更新Deployment失败: "+err.Error())
+		return
+	}
+
+	result, err := diff.Compute(live, dryRun)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "计算diff失败: "+err.Error())
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, result)
+}
+
+// readManifestBody 读取 YAML/JSON 请求体，Content-Type 不支持时直接写错误响应
+func readManifestBody(c *gin.Context) ([]byte, bool) {
+	contentType := c.ContentType()
+	if !strings.Contains(contentType, "yaml") && !strings.Contains(contentType, "x-yaml") && !strings.Contains(contentType, "json") {
+		respondError(c, http.StatusUnsupportedMediaType, "不支持的 Content-Type，请使用 application/json 或 application/yaml")
+		return nil, false
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "获取请求参数失败: "+err.Error())
+		return nil, false
+	}
+	return data, true
+}
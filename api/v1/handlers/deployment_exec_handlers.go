@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/ciliverse/cilikube/pkg/utils"
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/gin-gonic/gin"
+)
+
+// execUpgrader 将 HTTP 连接升级为 WebSocket，Origin 校验交由上层中间件处理
+var execUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// execFrame 是前端与后端之间约定的 WebSocket JSON 帧。
+// Data 为base64编码的原始字节，避免非UTF-8的终端字节流被JSON字符串编码静默损坏
+type execFrame struct {
+	Op   string `json:"op"` // "stdin" | "resize"
+	Data string `json:"data,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+}
+
+// ExecPod 在指定 Deployment 下的某个 Pod 中打开一个交互式 shell，
+// 通过 SPDY remotecommand 连接 kube-apiserver 的 pods/exec 子资源，
+// 并将 stdin/stdout/resize 桥接到浏览器端的 WebSocket 连接。
+func (h *DeploymentHandler) ExecPod(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	podName := c.Param("pod")
+
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+	if !utils.ValidateResourceName(podName) {
+		respondError(c, http.StatusBadRequest, "无效的Pod名称格式")
+		return
+	}
+
+	container := c.Query("container")
+	shell := c.DefaultQuery("shell", "/bin/sh")
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	// 校验该Pod确实属于目标Deployment（通过selector匹配），避免越权exec任意Pod
+	belongs, err := svc.PodBelongsToDeployment(namespace, name, podName)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "校验Pod归属失败: "+err.Error())
+		return
+	}
+	if !belongs {
+		respondError(c, http.StatusForbidden, "该Pod不属于指定的Deployment")
+		return
+	}
+
+	conn, err := execUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "升级WebSocket连接失败: "+err.Error())
+		return
+	}
+	defer conn.Close()
+
+	streamer := &execStreamer{conn: conn, resize: make(chan remotecommand.TerminalSize, 1)}
+
+	if err := svc.ExecInPod(namespace, podName, container, []string{shell}, streamer); err != nil {
+		_ = conn.WriteJSON(gin.H{"op": "error", "data": err.Error()})
+	}
+}
+
+// execStreamer 实现 remotecommand.TerminalSizeQueue 以及 io.Reader/io.Writer，
+// 在 WebSocket 帧与 kube-apiserver 的 exec 流之间转换
+type execStreamer struct {
+	conn      *websocket.Conn
+	resize    chan remotecommand.TerminalSize
+	remainder []byte // 上一帧未被caller读完的stdin字节，下次Read优先排空
+}
+
+// Read 实现 io.Reader：从 WebSocket 读取 stdin 帧并转发给 exec 流。
+// 单个WS帧的stdin payload可能大于调用方传入的p，多出的部分缓存在remainder中，
+// 在读取下一条WS消息之前优先排空，避免静默截断大段粘贴内容。
+func (s *execStreamer) Read(p []byte) (int, error) {
+	if len(s.remainder) > 0 {
+		n := copy(p, s.remainder)
+		s.remainder = s.remainder[n:]
+		return n, nil
+	}
+
+	for {
+		_, raw, err := s.conn.ReadMessage()
+		if err != nil {
+			return 0, io.EOF
+		}
+
+		var frame execFrame
+		if err := json.Unmarshal(raw, &frame); err != nil {
+			continue
+		}
+
+		switch frame.Op {
+		case "resize":
+			select {
+			case s.resize <- remotecommand.TerminalSize{Width: frame.Cols, Height: frame.Rows}:
+			default:
+			}
+			continue
+		case "stdin":
+			data, err := base64.StdEncoding.DecodeString(frame.Data)
+			if err != nil {
+				continue
+			}
+			n := copy(p, data)
+			if n < len(data) {
+				s.remainder = data[n:]
+			}
+			return n, nil
+		}
+	}
+}
+
+// Write 实现 io.Writer：将 exec 流的 stdout/stderr 转发为 WebSocket 帧，
+// data 经base64编码以保证二进制安全
+func (s *execStreamer) Write(p []byte) (int, error) {
+	if err := s.conn.WriteJSON(gin.H{"op": "stdout", "data": base64.StdEncoding.EncodeToString(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Next 实现 remotecommand.TerminalSizeQueue，关闭时返回 nil 以结束exec流
+func (s *execStreamer) Next() *remotecommand.TerminalSize {
+	size, ok := <-s.resize
+	if !ok {
+		return nil
+	}
+	return &size
+}
@@ -4,26 +4,53 @@ import (
 	"fmt"
 	"github.com/ciliverse/cilikube/api/v1/models"
 	"github.com/ciliverse/cilikube/internal/service"
+	"github.com/ciliverse/cilikube/pkg/cluster"
+	"github.com/ciliverse/cilikube/pkg/dataselect"
 	"github.com/ciliverse/cilikube/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"io"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DeploymentHandler ...
 type DeploymentHandler struct {
-	service *service.DeploymentService
+	service        *service.DeploymentService
+	clusterManager *cluster.Manager
 }
 
 // NewDeploymentHandler ...
-func NewDeploymentHandler(svc *service.DeploymentService) *DeploymentHandler {
-	return &DeploymentHandler{service: svc}
+func NewDeploymentHandler(svc *service.DeploymentService, clusterManager *cluster.Manager) *DeploymentHandler {
+	return &DeploymentHandler{service: svc, clusterManager: clusterManager}
+}
+
+// resolveService 根据 :cluster 路径参数解析出对应集群的 DeploymentService。
+// 未注册或不可达的集群返回 404，并由调用方直接 return。
+func (h *DeploymentHandler) resolveService(c *gin.Context) (*service.DeploymentService, bool) {
+	name := c.Param("cluster")
+	if name == "" {
+		return h.service, true
+	}
+
+	target, err := h.clusterManager.Get(name)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "集群不存在或不可达: "+err.Error())
+		return nil, false
+	}
+	if status, _, lastErr := target.Status(); status == cluster.StatusNotReady {
+		respondError(c, http.StatusNotFound, "集群不存在或不可达: "+lastErr)
+		return nil, false
+	}
+
+	return service.NewDeploymentService(target.Clientset, target.Config), true
 }
 
 // ListDeployments ...
@@ -35,21 +62,56 @@ func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
 		return
 	}
 
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
 	// 2. 调用服务层获取Deployment列表
-	deployments, err := h.service.List(namespace)
+	deployments, err := svc.List(namespace)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "获取Deployment列表失败: "+err.Error())
 		return
 	}
 
-	// 无数据的话slice未初始化，返回前端会是null，导致前端报错，特处理。如果前端可以处理，这个判断可删除
-	if len(deployments.Items) == 0 {
-		deployments.Items = make([]appsv1.Deployment, 0)
+	// 3. 按查询参数做服务端过滤/排序/分页，total为过滤后（分页前）的总数
+	query := parseDataSelectQuery(c)
+	cells, total := dataselect.GenericDataSelectWithTotal(dataselect.ToDeploymentCells(deployments.Items), query)
+
+	// 4. 返回统一的列表信封：{items, total}
+	respondSuccess(c, http.StatusOK, gin.H{
+		"items": dataselect.FromDeploymentCells(cells),
+		"total": total,
+	})
+}
+
+// parseDataSelectQuery 从请求的 query string 中解析出 DataSelectQuery
+// 支持 filter（按名称模糊过滤）、page/limit（分页）、sortBy=createTime|name、order=asc|desc（排序）
+func parseDataSelectQuery(c *gin.Context) *dataselect.DataSelectQuery {
+	query := dataselect.DefaultDataSelectQuery()
+
+	query.Filter.Name = c.Query("filter")
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil {
+		query.Paginate.Page = page
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		query.Paginate.Limit = limit
 	}
 
-	// 3. 返回结果
-	respondSuccess(c, http.StatusOK, deployments)
+	switch c.Query("sortBy") {
+	case "name":
+		query.Sort.By = dataselect.SortByName
+	case "createTime":
+		query.Sort.By = dataselect.SortByCreationTimestamp
+	}
+
+	query.Sort.Order = dataselect.SortAscending
+	if strings.EqualFold(c.Query("order"), "desc") {
+		query.Sort.Order = dataselect.SortDescending
+	}
 
+	return query
 }
 
 // CreateDeployment ...
@@ -61,18 +123,13 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 		return
 	}
 
-	contentType := c.ContentType()
-	var data []byte
-	var err error
+	data, ok := readManifestBody(c)
+	if !ok {
+		return
+	}
 
-	if strings.Contains(contentType, "yaml") || strings.Contains(contentType, "x-yaml") || strings.Contains(contentType, "json") {
-		data, err = io.ReadAll(c.Request.Body)
-		if err != nil {
-			respondError(c, http.StatusInternalServerError, "获取请求参数失败: "+err.Error())
-			return
-		}
-	} else {
-		respondError(c, http.StatusUnsupportedMediaType, "不支持的 Content-Type，请使用 application/json 或 application/yaml")
+	svc, ok := h.resolveService(c)
+	if !ok {
 		return
 	}
 
@@ -88,7 +145,7 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 	}
 
 	// 调用服务层创建Deployment
-	createdDeployment, err := h.service.Create(namespace, deployment)
+	createdDeployment, err := svc.Create(namespace, deployment)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
 			respondError(c, http.StatusConflict, "Deployment已存在")
@@ -116,8 +173,13 @@ func (h *DeploymentHandler) GetDeployment(c *gin.Context) {
 		return
 	}
 
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
 	// 2. 调用服务层获取Deployment详情
-	deployment, err := h.service.Get(namespace, name)
+	deployment, err := svc.Get(namespace, name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			respondError(c, http.StatusNotFound, "Deployment不存在")
@@ -148,17 +210,36 @@ func (h *DeploymentHandler) UpdateDeployment(c *gin.Context) {
 	}
 
 	contentType := c.ContentType()
-	var data []byte
-	var err error
+	data, ok := readManifestBody(c)
+	if !ok {
+		return
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	// patchType 非空表示本次请求走 Patch/Server-Side-Apply 更新模式，跳过整体替换
+	if patchType, isPatch := patchTypeForContentType(contentType); isPatch {
+		fieldManager := c.DefaultQuery("fieldManager", "cilikube")
+		force := c.Query("force") == "true"
 
-	if strings.Contains(contentType, "yaml") || strings.Contains(contentType, "x-yaml") || strings.Contains(contentType, "json") {
-		data, err = io.ReadAll(c.Request.Body)
+		resultDeployment, err := svc.Patch(namespace, name, patchType, data, fieldManager, force)
 		if err != nil {
-			respondError(c, http.StatusInternalServerError, "获取请求参数失败: "+err.Error())
+			if errors.IsNotFound(err) {
+				respondError(c, http.StatusNotFound, "Deployment不存在 (可能在更新期间被删除)")
+				return
+			}
+			if errors.IsConflict(err) {
+				respondError(c, http.StatusConflict, "Deployment已被修改，请重试 (ResourceVersion conflict，可使用 force=true 强制接管)")
+				return
+			}
+			respondError(c, http.StatusInternalServerError, "更新Deployment失败: "+err.Error())
 			return
 		}
-	} else {
-		respondError(c, http.StatusUnsupportedMediaType, "不支持的 Content-Type，请使用 application/json 或 application/yaml")
+
+		respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(resultDeployment))
 		return
 	}
 
@@ -174,7 +255,7 @@ func (h *DeploymentHandler) UpdateDeployment(c *gin.Context) {
 	}
 
 	// 调用服务层更新Deployment
-	resultDeployment, err := h.service.Update(namespace, name, updateDeployment)
+	resultDeployment, err := svc.Update(namespace, name, updateDeployment)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			respondError(c, http.StatusNotFound, "Deployment不存在 (可能在更新期间被删除)")
@@ -191,6 +272,23 @@ func (h *DeploymentHandler) UpdateDeployment(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(resultDeployment))
 }
 
+// patchTypeForContentType 将请求的 Content-Type 映射为 k8s types.PatchType，
+// 第二个返回值标识该 Content-Type 是否属于 patch/server-side-apply 更新模式
+func patchTypeForContentType(contentType string) (types.PatchType, bool) {
+	switch {
+	case strings.Contains(contentType, "apply-patch"):
+		return types.ApplyPatchType, true
+	case strings.Contains(contentType, "strategic-merge-patch"):
+		return types.StrategicMergePatchType, true
+	case strings.Contains(contentType, "merge-patch"):
+		return types.MergePatchType, true
+	case strings.Contains(contentType, "json-patch"):
+		return types.JSONPatchType, true
+	default:
+		return "", false
+	}
+}
+
 // DeleteDeployment ...
 func (h *DeploymentHandler) DeleteDeployment(c *gin.Context) {
 	namespace := c.Param("namespace")
@@ -206,7 +304,12 @@ func (h *DeploymentHandler) DeleteDeployment(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(namespace, name); err != nil {
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	if err := svc.Delete(namespace, name); err != nil {
 		if errors.IsNotFound(err) {
 			respondError(c, http.StatusNotFound, "Deployment不存在")
 			return
@@ -219,7 +322,19 @@ func (h *DeploymentHandler) DeleteDeployment(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, gin.H{"message": "删除成功"})
 }
 
-// WatchDeployments ...
+// watchReconnectBaseDelay/watchReconnectMaxDelay 控制Watch channel关闭后自动重连的退避策略
+const (
+	watchReconnectBaseDelay = 500 * time.Millisecond
+	watchReconnectMaxDelay  = 30 * time.Second
+	// watchReconnectJitterFraction 退避时长上叠加的随机抖动比例，避免大量客户端同时重连时集体打满apiserver
+	watchReconnectJitterFraction = 0.3
+)
+
+// WatchDeployments 以 SSE 的形式持续推送Deployment变更事件。
+// Watch channel 因apiserver侧瞬时抖动而关闭时，会带着最后一次观察到的resourceVersion
+// 透明地重新Watch（AllowWatchBookmarks=true），并向客户端发送一个携带退避时长的
+// "reconnect" 事件，而不是直接断开连接。Bookmark事件会作为独立的"bookmark" SSE类型转发，
+// 而不是被悄悄丢弃。
 func (h *DeploymentHandler) WatchDeployments(c *gin.Context) {
 	// 参数获取校验
 	namespace := strings.TrimSpace(c.Param("namespace"))
@@ -229,13 +344,29 @@ func (h *DeploymentHandler) WatchDeployments(c *gin.Context) {
 	}
 	labelSelector := c.Query("labelSelector")
 
-	// 创建 Deployment Watcher
-	watcher, err := h.service.Watch(namespace, labelSelector)
+	var timeoutSeconds *int64
+	if raw := c.Query("timeoutSeconds"); raw != "" {
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil && v > 0 {
+			timeoutSeconds = &v
+		}
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	lastResourceVersion := c.Query("resourceVersion")
+
+	watcher, err := svc.WatchFrom(namespace, labelSelector, lastResourceVersion, timeoutSeconds)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "开始监听Deployment失败: "+err.Error())
 		return
 	}
-	defer watcher.Stop()
+	// watcher会在重连时被替换，用闭包引用当前值，确保最终退出时停掉的是仍然存活的那一个
+	defer func() {
+		watcher.Stop()
+	}()
 
 	// 设置响应头为 text/event-stream，启用 SSE
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
@@ -243,31 +374,87 @@ func (h *DeploymentHandler) WatchDeployments(c *gin.Context) {
 	c.Writer.Header().Set("Connection", "keep-alive")
 	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// 使用 Gin 的流式响应
+	reconnectDelay := watchReconnectBaseDelay
+
+	// 使用 Gin 的流式响应；回调一直返回true直到客户端断开或重新Watch失败，内部自行处理重连
 	c.Stream(func(w io.Writer) bool {
-		for {
-			select {
-			case event, ok := <-watcher.ResultChan(): // channel 接收事件
-				if !ok {
-					// Watch channel 关闭，重新连接
-					fmt.Println("Watcher channel closed")
-					c.SSEvent("close", gin.H{"message": "Watcher channel closed"})
+		select {
+		case event, ok := <-watcher.ResultChan(): // channel 接收事件
+			if !ok {
+				// Watch channel 关闭：按退避时长（含抖动）通知客户端，再用最后观察到的resourceVersion重新Watch
+				delay := withJitter(reconnectDelay)
+				c.SSEvent("reconnect", gin.H{"delayMs": delay.Milliseconds(), "resourceVersion": lastResourceVersion})
+
+				select {
+				case <-time.After(delay):
+				case <-c.Request.Context().Done():
 					return false
 				}
+				if reconnectDelay < watchReconnectMaxDelay {
+					reconnectDelay *= 2
+					if reconnectDelay > watchReconnectMaxDelay {
+						reconnectDelay = watchReconnectMaxDelay
+					}
+				}
 
-				// 发送事件到客户端
-				c.SSEvent("message", toWatchDeploymentEvent(event))
+				watcher.Stop()
+				newWatcher, err := svc.WatchFrom(namespace, labelSelector, lastResourceVersion, timeoutSeconds)
+				if err != nil {
+					c.SSEvent("error", gin.H{"message": "重新监听Deployment失败: " + err.Error()})
+					return false
+				}
+				watcher = newWatcher
 				return true
+			}
 
-			case <-c.Request.Context().Done():
-				// 客户端断开连接
-				fmt.Println("Client disconnected from watch stream")
-				return false
+			if rv := resourceVersionOf(event); rv != "" {
+				lastResourceVersion = rv
 			}
+			reconnectDelay = watchReconnectBaseDelay
+
+			if event.Type == watch.Bookmark {
+				// Bookmark 作为独立的SSE类型转发，供客户端推进自己的resourceVersion
+				c.SSEvent("bookmark", gin.H{"resourceVersion": lastResourceVersion})
+				return true
+			}
+
+			// 发送事件到客户端
+			c.SSEvent("message", toWatchDeploymentEvent(event))
+			return true
+
+		case <-c.Request.Context().Done():
+			// 客户端断开连接
+			return false
 		}
 	})
 }
 
+// withJitter 在基础退避时长上叠加 ±watchReconnectJitterFraction 的随机抖动，
+// 避免同一时刻大量客户端集中重连对apiserver造成惊群效应
+func withJitter(base time.Duration) time.Duration {
+	jitterRange := int64(float64(base) * watchReconnectJitterFraction)
+	if jitterRange <= 0 {
+		return base
+	}
+	offset := rand.Int63n(2*jitterRange) - jitterRange
+	result := base + time.Duration(offset)
+	if result < 0 {
+		return base
+	}
+	return result
+}
+
+// resourceVersionOf 从Watch事件中提取resourceVersion，供重连时作为起点
+func resourceVersionOf(event watch.Event) string {
+	if deployment, ok := event.Object.(*appsv1.Deployment); ok {
+		return deployment.ResourceVersion
+	}
+	if accessor, ok := event.Object.(metav1.Object); ok {
+		return accessor.GetResourceVersion()
+	}
+	return ""
+}
+
 // ScaleDeployment ...
 func (h *DeploymentHandler) ScaleDeployment(c *gin.Context) {
 	namespace := c.Param("namespace")
@@ -291,8 +478,13 @@ func (h *DeploymentHandler) ScaleDeployment(c *gin.Context) {
 		return
 	}
 
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
 	// 2. 调用服务层修改Deployment的副本数
-	deployment, err := h.service.Scale(namespace, name, req.Replicas)
+	deployment, err := svc.Scale(namespace, name, *req.Replicas)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			respondError(c, http.StatusNotFound, "Deployment不存在")
@@ -306,6 +498,151 @@ func (h *DeploymentHandler) ScaleDeployment(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(deployment))
 }
 
+// GetDeploymentHistory 列出 Deployment 持有的历史 ReplicaSet（修订版本）
+func (h *DeploymentHandler) GetDeploymentHistory(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	// 1. 参数校验
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层获取修订历史
+	history, err := svc.History(namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "Deployment不存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "获取Deployment历史失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回结果
+	respondSuccess(c, http.StatusOK, history)
+}
+
+// RollbackDeployment 将 Deployment 回滚到指定的历史修订版本
+func (h *DeploymentHandler) RollbackDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var req models.RollbackDeploymentRequest
+
+	// 1. 参数校验
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "无效的Revision格式: "+err.Error())
+		return
+	}
+
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层回滚Deployment
+	deployment, err := svc.Rollback(namespace, name, req.Revision)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "Deployment或目标修订版本不存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "回滚Deployment失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回结果
+	respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(deployment))
+}
+
+// PauseDeployment 暂停 Deployment 的滚动更新（spec.paused = true）
+func (h *DeploymentHandler) PauseDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	deployment, err := svc.SetPaused(namespace, name, true)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "Deployment不存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "暂停Deployment失败: "+err.Error())
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(deployment))
+}
+
+// ResumeDeployment 恢复 Deployment 的滚动更新（spec.paused = false）
+func (h *DeploymentHandler) ResumeDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	if !utils.ValidateNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, "无效的命名空间格式")
+		return
+	}
+
+	if !utils.ValidateResourceName(name) {
+		respondError(c, http.StatusBadRequest, "无效的Deployment名称格式")
+		return
+	}
+
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
+	}
+
+	deployment, err := svc.SetPaused(namespace, name, false)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, "Deployment不存在")
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "恢复Deployment失败: "+err.Error())
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, models.ToDeploymentResponse(deployment))
+}
+
 // GetDeploymentPods ...
 func (h *DeploymentHandler) GetDeploymentPods(c *gin.Context) {
 	namespace := c.Param("namespace")
@@ -322,31 +659,37 @@ func (h *DeploymentHandler) GetDeploymentPods(c *gin.Context) {
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "500") // Sensible default limit
-	limit, err := strconv.ParseInt(limitStr, 10, 64)
-	if err != nil || limit <= 0 {
-		limit = 500 // Fallback
+	svc, ok := h.resolveService(c)
+	if !ok {
+		return
 	}
 
-	pods, err := h.service.PodList(namespace, name, limit)
+	// apiserver侧只做一次性粗拉取，?filter=/?page=/?limit=/?sortBy=/?order= 全部交给dataselect处理
+	pods, err := svc.PodList(namespace, name, podListFetchLimit)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "获取Pod列表失败: "+err.Error())
 		return
 	}
 
+	// 按查询参数做服务端过滤/排序/分页，total为过滤后（分页前）的总数
+	query := parseDataSelectQuery(c)
+	cells, total := dataselect.GenericDataSelectWithTotal(dataselect.ToPodCells(pods.Items), query)
+	selectedPods := dataselect.FromPodCells(cells)
+
 	response := models.PodListResponse{
-		Items: make([]models.PodResponse, 0, len(pods.Items)),
-		// Total reflects items *in this batch*. K8s list doesn't give total count easily.
-		Total: len(pods.Items),
+		Items: make([]models.PodResponse, 0, len(selectedPods)),
+		Total: total,
 	}
-
-	for _, pod := range pods.Items {
-		response.Items = append(response.Items, models.ToPodResponse(&pod))
+	for i := range selectedPods {
+		response.Items = append(response.Items, models.ToPodResponse(&selectedPods[i]))
 	}
 
 	respondSuccess(c, http.StatusOK, response)
 }
 
+// podListFetchLimit 是从apiserver一次性拉取Pod列表的上限，真正的分页由dataselect在内存中完成
+const podListFetchLimit = 2000
+
 // --- Helper Functions ---
 
 // toWatchDeploymentEvent ...
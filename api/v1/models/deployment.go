@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// DeploymentResponse 是对外暴露的Deployment视图，裁剪掉管理端不关心的内部字段
+type DeploymentResponse struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Replicas          int32             `json:"replicas"`
+	ReadyReplicas     int32             `json:"readyReplicas"`
+	UpdatedReplicas   int32             `json:"updatedReplicas"`
+	AvailableReplicas int32             `json:"availableReplicas"`
+	Paused            bool              `json:"paused"`
+	Images            []string          `json:"images"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+}
+
+// ToDeploymentResponse 将 appsv1.Deployment 转换为对外响应结构
+func ToDeploymentResponse(d *appsv1.Deployment) DeploymentResponse {
+	images := make([]string, 0, len(d.Spec.Template.Spec.Containers))
+	for _, ctr := range d.Spec.Template.Spec.Containers {
+		images = append(images, ctr.Image)
+	}
+
+	return DeploymentResponse{
+		Name:              d.Name,
+		Namespace:         d.Namespace,
+		Labels:            d.Labels,
+		Annotations:       d.Annotations,
+		Replicas:          d.Status.Replicas,
+		ReadyReplicas:     d.Status.ReadyReplicas,
+		UpdatedReplicas:   d.Status.UpdatedReplicas,
+		AvailableReplicas: d.Status.AvailableReplicas,
+		Paused:            d.Spec.Paused,
+		Images:            images,
+		CreationTimestamp: d.CreationTimestamp.Time,
+	}
+}
+
+// ScaleDeploymentRequest 是 ScaleDeployment 接口的请求体
+type ScaleDeploymentRequest struct {
+	Replicas *int32 `json:"replicas" binding:"required,min=0"`
+}
+
+// RollbackDeploymentRequest 是 RollbackDeployment 接口的请求体
+type RollbackDeploymentRequest struct {
+	Revision int64 `json:"revision" binding:"required,min=1"`
+}
+
+// ReplicaSetRevision 是 Deployment 历史中的一条记录：一个由Deployment拥有的ReplicaSet
+type ReplicaSetRevision struct {
+	Revision          int64     `json:"revision"`
+	ReplicaSetName    string    `json:"replicaSetName"`
+	Images            []string  `json:"images"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// DeploymentHistoryResponse 是 GetDeploymentHistory 接口的响应体
+type DeploymentHistoryResponse struct {
+	Items []ReplicaSetRevision `json:"items"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodResponse 是对外暴露的Pod视图
+type PodResponse struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Phase             string            `json:"phase"`
+	PodIP             string            `json:"podIP"`
+	NodeName          string            `json:"nodeName"`
+	Ready             bool              `json:"ready"`
+	RestartCount      int32             `json:"restartCount"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+}
+
+// ToPodResponse 将 corev1.Pod 转换为对外响应结构
+func ToPodResponse(pod *corev1.Pod) PodResponse {
+	ready := false
+	var restartCount int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		restartCount += cs.RestartCount
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			ready = true
+			break
+		}
+	}
+
+	return PodResponse{
+		Name:              pod.Name,
+		Namespace:         pod.Namespace,
+		Labels:            pod.Labels,
+		Phase:             string(pod.Status.Phase),
+		PodIP:             pod.Status.PodIP,
+		NodeName:          pod.Spec.NodeName,
+		Ready:             ready,
+		RestartCount:      restartCount,
+		CreationTimestamp: pod.CreationTimestamp.Time,
+	}
+}
+
+// PodListResponse 是 GetDeploymentPods 接口的响应体
+type PodListResponse struct {
+	Items []PodResponse `json:"items"`
+	Total int           `json:"total"`
+}
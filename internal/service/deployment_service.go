@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/ciliverse/cilikube/api/v1/models"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// revisionAnnotation 是 kube-controller-manager 写在 ReplicaSet 上、标记其所属
+// Deployment 修订版本号的annotation
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// DeploymentService 封装针对单个集群clientset的Deployment相关操作。
+// config 仅在需要直连apiserver建立SPDY流时使用（ExecInPod）。
+type DeploymentService struct {
+	clientset *kubernetes.Clientset
+	config    *rest.Config
+}
+
+// NewDeploymentService 基于一个clientset及其对应的rest.Config创建DeploymentService
+func NewDeploymentService(clientset *kubernetes.Clientset, config *rest.Config) *DeploymentService {
+	return &DeploymentService{clientset: clientset, config: config}
+}
+
+// List 返回命名空间下的全部Deployment
+func (s *DeploymentService) List(namespace string) (*appsv1.DeploymentList, error) {
+	return s.clientset.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
+}
+
+// Create 创建一个Deployment
+func (s *DeploymentService) Create(namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	return s.clientset.AppsV1().Deployments(namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+}
+
+// Get 获取单个Deployment
+func (s *DeploymentService) Get(namespace, name string) (*appsv1.Deployment, error) {
+	return s.clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+}
+
+// Update 整体替换一个Deployment
+func (s *DeploymentService) Update(namespace, name string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	deployment.Name = name
+	return s.clientset.AppsV1().Deployments(namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
+}
+
+// Delete 删除一个Deployment
+func (s *DeploymentService) Delete(namespace, name string) error {
+	return s.clientset.AppsV1().Deployments(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// CreateDryRun 以dry-run方式提交一次Deployment创建请求，不会真正持久化，
+// 返回apiserver（经准入webhook/默认值填充后）计算出的结果对象，供前端预览diff
+func (s *DeploymentService) CreateDryRun(namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	return s.clientset.AppsV1().Deployments(namespace).Create(context.Background(), deployment, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+}
+
+// UpdateDryRun 以dry-run方式提交一次Deployment整体更新请求，不会真正持久化，
+// 返回apiserver计算出的结果对象，供前端预览diff
+func (s *DeploymentService) UpdateDryRun(namespace, name string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	deployment.Name = name
+	return s.clientset.AppsV1().Deployments(namespace).Update(context.Background(), deployment, metav1.UpdateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+}
+
+// Patch 以Patch/Server-Side-Apply方式更新一个Deployment，fieldManager标识字段管理者，
+// force为true时在发生字段冲突时强制接管所有权（仅对Server-Side-Apply生效）
+func (s *DeploymentService) Patch(namespace, name string, patchType types.PatchType, data []byte, fieldManager string, force bool) (*appsv1.Deployment, error) {
+	opts := metav1.PatchOptions{FieldManager: fieldManager}
+	// apiserver 拒绝非apply类型的patch携带force字段，因此只在Server-Side-Apply时设置
+	if patchType == types.ApplyPatchType {
+		opts.Force = &force
+	}
+	return s.clientset.AppsV1().Deployments(namespace).Patch(context.Background(), name, patchType, data, opts)
+}
+
+// Watch 从当前resourceVersion开始监听命名空间下的Deployment变更
+func (s *DeploymentService) Watch(namespace, labelSelector string) (watch.Interface, error) {
+	return s.clientset.AppsV1().Deployments(namespace).Watch(context.Background(), metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+}
+
+// WatchFrom 从指定resourceVersion开始监听命名空间下的Deployment变更，resourceVersion为空时
+// 等价于从当前状态开始监听。开启AllowWatchBookmarks以便客户端可以推进自己的resourceVersion，
+// timeoutSeconds非空时限制本次watch连接的最长存活时间，供调用方做周期性重连
+func (s *DeploymentService) WatchFrom(namespace, labelSelector, resourceVersion string, timeoutSeconds *int64) (watch.Interface, error) {
+	return s.clientset.AppsV1().Deployments(namespace).Watch(context.Background(), metav1.ListOptions{
+		LabelSelector:       labelSelector,
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+		TimeoutSeconds:      timeoutSeconds,
+	})
+}
+
+// Scale 修改Deployment的副本数
+func (s *DeploymentService) Scale(namespace, name string, replicas int32) (*appsv1.Deployment, error) {
+	deployment, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Spec.Replicas = &replicas
+	return s.Update(namespace, name, deployment)
+}
+
+// SetPaused 设置Deployment的 spec.paused，供Pause/Resume接口复用
+func (s *DeploymentService) SetPaused(namespace, name string, paused bool) (*appsv1.Deployment, error) {
+	deployment, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Spec.Paused = paused
+	return s.Update(namespace, name, deployment)
+}
+
+// PodList 返回属于指定Deployment的Pod列表（按Deployment的selector过滤），limit控制
+// 单次向apiserver请求的Pod数量上限
+func (s *DeploymentService) PodList(namespace, name string, limit int64) (*corev1.PodList, error) {
+	deployment, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("解析Deployment selector失败: %w", err)
+	}
+
+	return s.clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+		Limit:         limit,
+	})
+}
+
+// PodBelongsToDeployment 判断某个Pod是否匹配Deployment的selector，用于exec前的越权校验
+func (s *DeploymentService) PodBelongsToDeployment(namespace, deploymentName, podName string) (bool, error) {
+	deployment, err := s.Get(namespace, deploymentName)
+	if err != nil {
+		return false, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return false, fmt.Errorf("解析Deployment selector失败: %w", err)
+	}
+
+	pod, err := s.clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(pod.Labels)), nil
+}
+
+// ExecStream 是 ExecInPod 所需的双向流：stdin/stdout的读写 + 终端resize事件队列
+type ExecStream interface {
+	io.Reader
+	io.Writer
+	remotecommand.TerminalSizeQueue
+}
+
+// ExecInPod 在指定Pod中打开一个SPDY exec流并桥接到stream
+func (s *DeploymentService) ExecInPod(namespace, podName, container string, command []string, stream ExecStream) error {
+	req := s.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(s.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("创建SPDY executor失败: %w", err)
+	}
+
+	return executor.Stream(remotecommand.StreamOptions{
+		Stdin:             stream,
+		Stdout:            stream,
+		Stderr:            stream,
+		Tty:               true,
+		TerminalSizeQueue: stream,
+	})
+}
+
+// History 列出Deployment持有的历史ReplicaSet（按revision降序）
+func (s *DeploymentService) History(namespace, name string) (*models.DeploymentHistoryResponse, error) {
+	deployment, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("解析Deployment selector失败: %w", err)
+	}
+
+	rsList, err := s.clientset.AppsV1().ReplicaSets(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.ReplicaSetRevision, 0, len(rsList.Items))
+	for _, rs := range rsList.Items {
+		if !ownedByDeployment(rs.OwnerReferences, deployment.Name, deployment.UID) {
+			continue
+		}
+
+		revision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		images := make([]string, 0, len(rs.Spec.Template.Spec.Containers))
+		for _, ctr := range rs.Spec.Template.Spec.Containers {
+			images = append(images, ctr.Image)
+		}
+
+		items = append(items, models.ReplicaSetRevision{
+			Revision:          revision,
+			ReplicaSetName:    rs.Name,
+			Images:            images,
+			CreationTimestamp: rs.CreationTimestamp.Time,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Revision > items[j].Revision })
+
+	return &models.DeploymentHistoryResponse{Items: items}, nil
+}
+
+// Rollback 将Deployment的 spec.template 回滚为指定revision对应ReplicaSet的模板
+func (s *DeploymentService) Rollback(namespace, name string, revision int64) (*appsv1.Deployment, error) {
+	deployment, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("解析Deployment selector失败: %w", err)
+	}
+
+	rsList, err := s.clientset.AppsV1().ReplicaSets(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rs := range rsList.Items {
+		if !ownedByDeployment(rs.OwnerReferences, deployment.Name, deployment.UID) {
+			continue
+		}
+		rsRevision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if rsRevision != revision {
+			continue
+		}
+
+		deployment.Spec.Template = rs.Spec.Template
+		return s.Update(namespace, name, deployment)
+	}
+
+	return nil, errors.NewNotFound(appsv1.Resource("replicasets"), fmt.Sprintf("revision %d", revision))
+}
+
+func ownedByDeployment(refs []metav1.OwnerReference, name string, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.Kind == "Deployment" && ref.Name == name && ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+// Package cluster 维护后端同时管理的多个 Kubernetes 集群及其 clientset，
+// 并周期性探测各集群的健康状态。
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Source 标记集群 clientset 的注册来源
+type Source string
+
+const (
+	// SourceUpload 通过上传 kubeconfig 文件注册
+	SourceUpload Source = "upload"
+	// SourceSecret 通过引用 Secret 中的 kubeconfig 注册
+	SourceSecret Source = "secret"
+	// SourceInCluster 使用 in-cluster 配置注册（通常是宿主集群自身）
+	SourceInCluster Source = "in-cluster"
+)
+
+// Status 是集群最近一次健康探测得出的状态
+type Status string
+
+const (
+	// StatusUnknown 尚未完成过一次探测
+	StatusUnknown Status = "unknown"
+	// StatusReady 最近一次探测成功
+	StatusReady Status = "ready"
+	// StatusNotReady 最近一次探测失败
+	StatusNotReady Status = "notReady"
+)
+
+// probeTimeout 是单次健康探测允许的最长耗时，避免某个失联集群拖慢整轮探测
+const probeTimeout = 5 * time.Second
+
+// Cluster 是一个已注册集群的运行时信息，Status 相关字段由探测goroutine异步更新，
+// 用独立的锁与注册信息（Name/Source/Clientset/Config）隔离，避免探测期间阻塞Get/List
+type Cluster struct {
+	Name      string
+	Source    Source
+	Clientset *kubernetes.Clientset
+	Config    *rest.Config
+
+	statusMu    sync.RWMutex
+	status      Status
+	lastChecked time.Time
+	lastError   string
+}
+
+// Status 返回该集群最近一次探测得到的状态、探测时间与错误信息（若有）
+func (c *Cluster) Status() (status Status, lastChecked time.Time, lastError string) {
+	c.statusMu.RLock()
+	defer c.statusMu.RUnlock()
+	return c.status, c.lastChecked, c.lastError
+}
+
+func (c *Cluster) setStatus(status Status, err error) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	c.status = status
+	c.lastChecked = time.Now()
+	if err != nil {
+		c.lastError = err.Error()
+	} else {
+		c.lastError = ""
+	}
+}
+
+// Manager 维护 name -> Cluster 的注册表，供 Handler 按 :cluster 路径参数解析 clientset，
+// 并在后台周期性探测每个已注册集群的健康状态
+type Manager struct {
+	mu       sync.RWMutex
+	clusters map[string]*Cluster
+}
+
+// NewManager 创建一个空的 ClusterManager
+func NewManager() *Manager {
+	return &Manager{clusters: make(map[string]*Cluster)}
+}
+
+// Get 按名称返回已注册的集群，集群不存在时返回 error
+func (m *Manager) Get(name string) (*Cluster, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cluster, ok := m.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("集群 %q 未注册", name)
+	}
+	return cluster, nil
+}
+
+// List 返回当前已注册的全部集群（按注册顺序无保证）
+func (m *Manager) List() []*Cluster {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clusters := make([]*Cluster, 0, len(m.clusters))
+	for _, cluster := range m.clusters {
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// RegisterFromKubeconfig 通过 kubeconfig 字节内容（上传或 Secret 引用）注册一个集群
+func (m *Manager) RegisterFromKubeconfig(name string, source Source, kubeconfig []byte) (*Cluster, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("解析kubeconfig失败: %w", err)
+	}
+	return m.register(name, source, config)
+}
+
+// RegisterInCluster 使用 in-cluster 配置注册宿主集群自身
+func (m *Manager) RegisterInCluster(name string) (*Cluster, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("获取in-cluster配置失败: %w", err)
+	}
+	return m.register(name, SourceInCluster, config)
+}
+
+// ErrAlreadyRegistered 表示待注册的集群名称已存在，调用方应将其映射为 409
+var ErrAlreadyRegistered = errors.New("集群已存在")
+
+func (m *Manager) register(name string, source Source, config *rest.Config) (*Cluster, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("创建clientset失败: %w", err)
+	}
+
+	cluster := &Cluster{Name: name, Source: source, Clientset: clientset, Config: config, status: StatusUnknown}
+
+	// 存在性检查与写入必须在同一次加锁内完成，否则两个并发的AddCluster请求可能都通过
+	// Get()的预检查，进而互相覆盖对方刚写入的clientset
+	m.mu.Lock()
+	if _, exists := m.clusters[name]; exists {
+		m.mu.Unlock()
+		return nil, ErrAlreadyRegistered
+	}
+	m.clusters[name] = cluster
+	m.mu.Unlock()
+
+	// 注册后立即探测一次，避免新集群在下一个探测周期到来前一直显示unknown
+	go m.probe(cluster)
+
+	return cluster, nil
+}
+
+// Remove 注销一个已注册的集群
+func (m *Manager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clusters, name)
+}
+
+// probe 对单个集群做一次健康探测（请求 /healthz），并更新其Status
+func (m *Manager) probe(cluster *Cluster) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	_, err := cluster.Clientset.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(ctx)
+	if err != nil {
+		cluster.setStatus(StatusNotReady, err)
+		return
+	}
+	cluster.setStatus(StatusReady, nil)
+}
+
+// StartProbing 启动一个后台goroutine，每隔 interval 对所有已注册集群做一次并发健康探测，
+// 直到 ctx 被取消。调用方通常在服务启动时调用一次并保留其 ctx 的cancel函数用于优雅退出。
+func (m *Manager) StartProbing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, cluster := range m.List() {
+					go m.probe(cluster)
+				}
+			}
+		}
+	}()
+}
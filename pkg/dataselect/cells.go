@@ -0,0 +1,58 @@
+package dataselect
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// deploymentCell 让 appsv1.Deployment 满足 DataCell / LabeledCell
+type deploymentCell appsv1.Deployment
+
+func (d deploymentCell) GetName() string              { return d.Name }
+func (d deploymentCell) GetCreation() time.Time       { return d.CreationTimestamp.Time }
+func (d deploymentCell) GetLabels() map[string]string { return d.Labels }
+
+// ToDeploymentCells 将 Deployment 切片包装为 DataCell 切片
+func ToDeploymentCells(deployments []appsv1.Deployment) []DataCell {
+	cells := make([]DataCell, 0, len(deployments))
+	for i := range deployments {
+		cells = append(cells, deploymentCell(deployments[i]))
+	}
+	return cells
+}
+
+// FromDeploymentCells 将 DataCell 切片还原为 Deployment 切片
+func FromDeploymentCells(cells []DataCell) []appsv1.Deployment {
+	deployments := make([]appsv1.Deployment, 0, len(cells))
+	for _, c := range cells {
+		deployments = append(deployments, appsv1.Deployment(c.(deploymentCell)))
+	}
+	return deployments
+}
+
+// podCell 让 corev1.Pod 满足 DataCell / LabeledCell
+type podCell corev1.Pod
+
+func (p podCell) GetName() string              { return p.Name }
+func (p podCell) GetCreation() time.Time       { return p.CreationTimestamp.Time }
+func (p podCell) GetLabels() map[string]string { return p.Labels }
+
+// ToPodCells 将 Pod 切片包装为 DataCell 切片
+func ToPodCells(pods []corev1.Pod) []DataCell {
+	cells := make([]DataCell, 0, len(pods))
+	for i := range pods {
+		cells = append(cells, podCell(pods[i]))
+	}
+	return cells
+}
+
+// FromPodCells 将 DataCell 切片还原为 Pod 切片
+func FromPodCells(cells []DataCell) []corev1.Pod {
+	pods := make([]corev1.Pod, 0, len(cells))
+	for _, c := range cells {
+		pods = append(pods, corev1.Pod(c.(podCell)))
+	}
+	return pods
+}
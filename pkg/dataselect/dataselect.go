@@ -0,0 +1,189 @@
+// Package dataselect 提供列表接口通用的过滤、排序、分页能力。
+// 设计上参考 kubernetes-dashboard 的 DataCell/DataSelectQuery 模式：
+// 任意资源只需实现 DataCell 接口，即可复用同一套筛选/排序/分页逻辑。
+package dataselect
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DataCell 是可被 dataSelector 处理的最小资源单元。
+type DataCell interface {
+	GetName() string
+	GetCreation() time.Time
+}
+
+// SortOrder 排序方向
+type SortOrder string
+
+const (
+	// SortAscending 升序
+	SortAscending SortOrder = "asc"
+	// SortDescending 降序
+	SortDescending SortOrder = "desc"
+)
+
+// SortBy 目前支持的排序字段
+type SortBy string
+
+const (
+	// SortByName 按名称排序，对应 ?sortBy=name
+	SortByName SortBy = "name"
+	// SortByCreationTimestamp 按创建时间排序，对应 ?sortBy=createTime
+	SortByCreationTimestamp SortBy = "createTime"
+)
+
+// Filter 过滤条件
+type Filter struct {
+	// Name 为名称的子串匹配（不区分大小写）
+	Name string
+	// Labels 为精确匹配的标签集合
+	Labels map[string]string
+}
+
+// Paginate 分页参数，Page 从 1 开始
+type Paginate struct {
+	Page  int
+	Limit int
+}
+
+// Sort 排序参数
+type Sort struct {
+	By    SortBy
+	Order SortOrder
+}
+
+// DataSelectQuery 汇总了一次列表查询的筛选、排序、分页条件
+type DataSelectQuery struct {
+	Filter   Filter
+	Sort     Sort
+	Paginate Paginate
+}
+
+// DefaultDataSelectQuery 返回一个不做任何筛选/排序/分页的查询
+func DefaultDataSelectQuery() *DataSelectQuery {
+	return &DataSelectQuery{}
+}
+
+// dataSelector 持有待处理的数据集合与查询条件，实现 sort.Interface 以支持排序
+type dataSelector struct {
+	GenericDataList []DataCell
+	DataSelectQuery *DataSelectQuery
+}
+
+// Len 实现 sort.Interface
+func (d *dataSelector) Len() int { return len(d.GenericDataList) }
+
+// Swap 实现 sort.Interface
+func (d *dataSelector) Swap(i, j int) {
+	d.GenericDataList[i], d.GenericDataList[j] = d.GenericDataList[j], d.GenericDataList[i]
+}
+
+// Less 实现 sort.Interface
+func (d *dataSelector) Less(i, j int) bool {
+	a, b := d.GenericDataList[i], d.GenericDataList[j]
+
+	var less bool
+	switch d.DataSelectQuery.Sort.By {
+	case SortByCreationTimestamp:
+		less = a.GetCreation().Before(b.GetCreation())
+	default:
+		less = a.GetName() < b.GetName()
+	}
+
+	if d.DataSelectQuery.Sort.Order == SortDescending {
+		return !less
+	}
+	return less
+}
+
+// Sort 按 DataSelectQuery.Sort 对数据集合排序
+func (d *dataSelector) Sort() *dataSelector {
+	if d.DataSelectQuery.Sort.By == "" {
+		return d
+	}
+	sort.Stable(d)
+	return d
+}
+
+// Filter 按 DataSelectQuery.Filter 过滤数据集合
+func (d *dataSelector) Filter() *dataSelector {
+	f := d.DataSelectQuery.Filter
+	if f.Name == "" && len(f.Labels) == 0 {
+		return d
+	}
+
+	filtered := make([]DataCell, 0, len(d.GenericDataList))
+	for _, item := range d.GenericDataList {
+		if f.Name != "" && !strings.Contains(strings.ToLower(item.GetName()), strings.ToLower(f.Name)) {
+			continue
+		}
+		if labeled, ok := item.(LabeledCell); ok && len(f.Labels) > 0 {
+			if !matchLabels(labeled.GetLabels(), f.Labels) {
+				continue
+			}
+		}
+		filtered = append(filtered, item)
+	}
+	d.GenericDataList = filtered
+	return d
+}
+
+// Paginate 按 DataSelectQuery.Paginate 对数据集合分页
+func (d *dataSelector) Paginate() *dataSelector {
+	p := d.DataSelectQuery.Paginate
+	if p.Limit <= 0 {
+		return d
+	}
+	page := p.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	start := (page - 1) * p.Limit
+	if start >= len(d.GenericDataList) {
+		d.GenericDataList = []DataCell{}
+		return d
+	}
+	end := start + p.Limit
+	if end > len(d.GenericDataList) {
+		end = len(d.GenericDataList)
+	}
+	d.GenericDataList = d.GenericDataList[start:end]
+	return d
+}
+
+// LabeledCell 是可选接口，供需要按标签过滤的 DataCell 实现
+type LabeledCell interface {
+	GetLabels() map[string]string
+}
+
+func matchLabels(have, want map[string]string) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// GenericDataSelect 对任意 DataCell 集合执行过滤->排序->分页，返回处理后的结果
+func GenericDataSelect(list []DataCell, query *DataSelectQuery) []DataCell {
+	items, _ := GenericDataSelectWithTotal(list, query)
+	return items
+}
+
+// GenericDataSelectWithTotal 对任意 DataCell 集合执行过滤->排序->分页，
+// 同时返回过滤后（分页前）的总数，供前端分页组件使用
+func GenericDataSelectWithTotal(list []DataCell, query *DataSelectQuery) ([]DataCell, int) {
+	if query == nil {
+		query = DefaultDataSelectQuery()
+	}
+	selector := &dataSelector{GenericDataList: list, DataSelectQuery: query}
+	selector.Filter()
+	total := len(selector.GenericDataList)
+	items := selector.Sort().Paginate().GenericDataList
+	return items, total
+}
@@ -0,0 +1,78 @@
+// Package diff 提供资源对象间的统一文本diff与JSON Patch计算，
+// 供 dry-run 预览类接口（如 Deployment 的 /diff）复用。
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Result 同时包含适合人眼阅读的统一diff文本，以及适合前端按字段渲染的JSON Patch数组
+type Result struct {
+	Unified   string                       `json:"unified"`
+	JSONPatch []jsonpatch.JsonPatchOperation `json:"jsonPatch"`
+}
+
+// noiseFields 是apiserver在每次请求间都会变化、与“这次改动了什么”无关的字段，
+// diff前先从 live 与 dryRun 两侧剥离，否则几乎每次diff都会被这些字段淹没
+var noiseFields = []string{"resourceVersion", "managedFields", "generation", "uid", "creationTimestamp"}
+
+// Compute 计算 live（当前集群中的对象）与 dryRun（dry-run apply 后apiserver返回的对象）之间的差异。
+// 两者都先剥离噪声字段、格式化为带缩进的JSON，再生成统一diff，便于阅读。
+func Compute(live, dryRun interface{}) (*Result, error) {
+	liveJSON, err := marshalStripped(live)
+	if err != nil {
+		return nil, fmt.Errorf("序列化当前对象失败: %w", err)
+	}
+	dryRunJSON, err := marshalStripped(dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("序列化dry-run结果失败: %w", err)
+	}
+
+	unified := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(liveJSON)),
+		B:        difflib.SplitLines(string(dryRunJSON)),
+		FromFile: "live",
+		ToFile:   "dry-run",
+		Context:  3,
+	}
+	unifiedText, err := difflib.GetUnifiedDiffString(unified)
+	if err != nil {
+		return nil, fmt.Errorf("生成统一diff失败: %w", err)
+	}
+
+	patch, err := jsonpatch.CreatePatch(liveJSON, dryRunJSON)
+	if err != nil {
+		return nil, fmt.Errorf("生成JSON Patch失败: %w", err)
+	}
+
+	return &Result{Unified: unifiedText, JSONPatch: patch}, nil
+}
+
+// marshalStripped 将 v 序列化为JSON后剥离 noiseFields 与 status 字段，再以带缩进的格式输出，
+// 使diff只反映spec/metadata中真正有意义的改动
+func marshalStripped(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		// v 不是一个JSON对象（例如数组或标量），无需剥离，原样返回缩进版本；
+		// nil（"null"）会被解码为空map而不走这里，最终同样输出"null"
+		return json.MarshalIndent(v, "", "  ")
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range noiseFields {
+			delete(metadata, field)
+		}
+	}
+	delete(obj, "status")
+
+	return json.MarshalIndent(obj, "", "  ")
+}